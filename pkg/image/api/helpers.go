@@ -0,0 +1,141 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/kubernetes/pkg/api/unversioned"
+	"k8s.io/kubernetes/pkg/fields"
+)
+
+// ImageStreamToSelectableFields returns a field set that can be used for filter selection.
+func ImageStreamToSelectableFields(ir *ImageStream) fields.Set {
+	return fields.Set{
+		"metadata.name":                ir.Name,
+		"spec.dockerImageRepository":   ir.Spec.DockerImageRepository,
+		"status.dockerImageRepository": ir.Status.DockerImageRepository,
+	}
+}
+
+// AddTagEventToImageStream records next as the most recent entry for tag in stream.Status.Tags,
+// returning true if a new history entry was created. If the most recent entry already refers to
+// the same image, its DockerImageReference is refreshed in place instead of growing the history.
+func AddTagEventToImageStream(stream *ImageStream, tag string, next TagEvent) bool {
+	if stream.Status.Tags == nil {
+		stream.Status.Tags = make(map[string]TagEventList)
+	}
+	tags, ok := stream.Status.Tags[tag]
+	if !ok || len(tags.Items) == 0 {
+		stream.Status.Tags[tag] = TagEventList{Items: []TagEvent{next}}
+		return true
+	}
+
+	previous := tags.Items[0]
+	if previous.Image == next.Image {
+		previous.DockerImageReference = next.DockerImageReference
+		previous.Generation = next.Generation
+		tags.Items[0] = previous
+		stream.Status.Tags[tag] = tags
+		return false
+	}
+
+	tags.Items = append([]TagEvent{next}, tags.Items...)
+	stream.Status.Tags[tag] = tags
+	return true
+}
+
+// LatestTaggedImage returns the most recent TagEvent for tag, or nil if the tag has no history.
+// An empty tag defaults to DefaultImageTag.
+func LatestTaggedImage(stream *ImageStream, tag string) *TagEvent {
+	if len(tag) == 0 {
+		tag = DefaultImageTag
+	}
+	events, ok := stream.Status.Tags[tag]
+	if !ok || len(events.Items) == 0 {
+		return nil
+	}
+	event := events.Items[0]
+	return &event
+}
+
+// ResolveImageID finds a TagEvent across every tag in stream whose Image has the (possibly
+// abbreviated) imageID as a prefix, returning an error if no image or more than one distinct
+// image matches. The same image recorded under several tags counts as one match.
+func ResolveImageID(stream *ImageStream, imageID string) (*TagEvent, error) {
+	var match *TagEvent
+	for _, history := range stream.Status.Tags {
+		for i := range history.Items {
+			event := history.Items[i]
+			if !strings.HasPrefix(event.Image, imageID) {
+				continue
+			}
+			if match != nil && match.Image != event.Image {
+				return nil, fmt.Errorf("image identifier %q is not unique in %s/%s", imageID, stream.Namespace, stream.Name)
+			}
+			match = &event
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("no image matching %q exists in %s/%s", imageID, stream.Namespace, stream.Name)
+	}
+	return match, nil
+}
+
+// tagEventsEqual returns true if two TagEvents for the same tag represent the same image.
+func tagEventsEqual(a, b TagEvent) bool {
+	return a.Image == b.Image && a.DockerImageReference == b.DockerImageReference
+}
+
+// UpdateChangedTrackingTags propagates the newly tagged image on any spec tag to every other spec
+// tag in stream that tracks it via Reference: true and an ImageStreamTag `from`, so that renaming
+// what `:latest` points to also refreshes a tag like `:stable` that was defined as "track :latest".
+// A Reference: true ImageStreamImage `from`, such as a manifest list's per-platform child, pins an
+// immutable digest with no "latest" to follow, so it has nothing for this function to propagate; it
+// is refreshed directly by tagsChanged whenever its parent manifest list tag re-resolves instead.
+// It returns the number of tracking tags that were updated.
+func UpdateChangedTrackingTags(stream, oldStream *ImageStream) int {
+	updated := 0
+	for tag, tagRef := range stream.Spec.Tags {
+		if !tagRef.Reference || tagRef.From == nil || tagRef.From.Kind != "ImageStreamTag" {
+			continue
+		}
+		from := tagRef.From.Name
+		if tagRef.From.Namespace != "" && tagRef.From.Namespace != stream.Namespace {
+			// tracking is only automatic within the same stream; a cross-
+			// namespace tracking tag is refreshed by tagsChanged directly.
+			continue
+		}
+		fromTag := from
+		if idx := strings.Index(from, ":"); idx != -1 {
+			if from[:idx] != stream.Name {
+				// tracks a tag on another stream in this namespace
+				continue
+			}
+			fromTag = from[idx+1:]
+		}
+		latest := LatestTaggedImage(stream, fromTag)
+		if latest == nil {
+			continue
+		}
+		existing, ok := stream.Status.Tags[tag]
+		if ok && len(existing.Items) > 0 && tagEventsEqual(existing.Items[0], *latest) {
+			continue
+		}
+		event := *latest
+		event.Created = unversioned.Now()
+		if tagRef.Generation != nil {
+			event.Generation = *tagRef.Generation
+		}
+		AddTagEventToImageStream(stream, tag, event)
+		updated++
+	}
+	return updated
+}
+
+// ManifestListChild is a single platform-specific image resolved from an OCI or Docker manifest
+// list.
+type ManifestListChild struct {
+	Os           string
+	Architecture string
+	TagEvent     TagEvent
+}
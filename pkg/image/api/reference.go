@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DockerImageReference points to a Docker image.
+type DockerImageReference struct {
+	Registry  string
+	Namespace string
+	Name      string
+	Tag       string
+	ID        string
+}
+
+// ParseDockerImageReference parses a Docker pull spec string into a DockerImageReference, accepting
+// an optional registry host, namespace, and either a :tag or an @id (including a full digest, e.g.
+// "sha256:<hex>").
+func ParseDockerImageReference(spec string) (DockerImageReference, error) {
+	var ref DockerImageReference
+
+	namePart := spec
+	if at := strings.LastIndex(spec, "@"); at != -1 {
+		namePart, ref.ID = spec[:at], spec[at+1:]
+		if len(ref.ID) == 0 {
+			return ref, fmt.Errorf("the image reference %q must have an image id after '@'", spec)
+		}
+	} else if colon := strings.LastIndex(spec, ":"); colon != -1 && !strings.Contains(spec[colon:], "/") {
+		namePart, ref.Tag = spec[:colon], spec[colon+1:]
+		if len(ref.Tag) == 0 {
+			return ref, fmt.Errorf("the image reference %q must have a tag after ':'", spec)
+		}
+	}
+
+	segments := strings.Split(namePart, "/")
+	switch len(segments) {
+	case 1:
+		ref.Name = segments[0]
+	case 2:
+		ref.Namespace, ref.Name = segments[0], segments[1]
+	case 3:
+		ref.Registry, ref.Namespace, ref.Name = segments[0], segments[1], segments[2]
+	default:
+		return ref, fmt.Errorf("the image reference %q is invalid", spec)
+	}
+	if len(ref.Name) == 0 {
+		return ref, fmt.Errorf("the image reference %q is invalid", spec)
+	}
+	return ref, nil
+}
+
+// Exact returns a string representation that can be used to pull or reference this image precisely,
+// preferring the ID (digest) over the tag when both are present.
+func (r DockerImageReference) Exact() string {
+	name := r.String()
+	switch {
+	case len(r.ID) > 0:
+		return fmt.Sprintf("%s@%s", r.AsRepository().String(), r.ID)
+	default:
+		return name
+	}
+}
+
+// AsRepository returns the reference with the tag and ID cleared.
+func (r DockerImageReference) AsRepository() DockerImageReference {
+	r.Tag = ""
+	r.ID = ""
+	return r
+}
+
+// String returns the string representation of this reference.
+func (r DockerImageReference) String() string {
+	var out []string
+	if len(r.Registry) > 0 {
+		out = append(out, r.Registry)
+	}
+	if len(r.Namespace) > 0 {
+		out = append(out, r.Namespace)
+	}
+	out = append(out, r.Name)
+	name := strings.Join(out, "/")
+	switch {
+	case len(r.Tag) > 0:
+		return fmt.Sprintf("%s:%s", name, r.Tag)
+	case len(r.ID) > 0:
+		return fmt.Sprintf("%s@%s", name, r.ID)
+	default:
+		return name
+	}
+}
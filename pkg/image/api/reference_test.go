@@ -0,0 +1,32 @@
+package api
+
+import "testing"
+
+func TestParseDockerImageReferenceAcceptsFullDigest(t *testing.T) {
+	ref, err := ParseDockerImageReference("registry.example.com/ns/repo@sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ref.Registry != "registry.example.com" || ref.Namespace != "ns" || ref.Name != "repo" {
+		t.Fatalf("unexpected name parts: %#v", ref)
+	}
+	if ref.ID != "sha256:e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855" {
+		t.Fatalf("expected the full digest to be preserved as ID, got %q", ref.ID)
+	}
+	if ref.Tag != "" {
+		t.Fatalf("expected no tag to be set for a digest reference, got %q", ref.Tag)
+	}
+}
+
+func TestParseDockerImageReferenceRejectsMissingDigest(t *testing.T) {
+	if _, err := ParseDockerImageReference("registry.example.com/ns/repo@"); err == nil {
+		t.Fatalf("expected an error for a reference with an empty digest")
+	}
+}
+
+func TestDockerImageReferenceExactPrefersDigest(t *testing.T) {
+	ref := DockerImageReference{Registry: "registry.example.com", Namespace: "ns", Name: "repo", Tag: "latest", ID: "sha256:abc"}
+	if exact := ref.Exact(); exact != "registry.example.com/ns/repo@sha256:abc" {
+		t.Fatalf("expected Exact() to address by digest, got %q", exact)
+	}
+}
@@ -0,0 +1,93 @@
+package api
+
+import (
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/api/unversioned"
+)
+
+// DefaultImageTag is used when an image tag reference is not specific.
+const DefaultImageTag = "latest"
+
+// ImageStream stores a mapping of tags to images, metadata overrides that are applied
+// when images are tagged in a stream, and an optional reference to a Docker image
+// repository on a registry.
+type ImageStream struct {
+	unversioned.TypeMeta
+	kapi.ObjectMeta
+
+	// Spec describes the desired state of this stream.
+	Spec ImageStreamSpec
+	// Status describes the status of this stream.
+	Status ImageStreamStatus
+}
+
+// ImageStreamSpec represents the desired state of an ImageStream.
+type ImageStreamSpec struct {
+	// DockerImageRepository is optional, if specified this stream is backed by a Docker repository
+	// on this server.
+	DockerImageRepository string
+	// Tags map arbitrary string values to specific image locators.
+	Tags map[string]TagReference
+}
+
+// TagReference specifies optional annotations for images using this tag and an optional reference to
+// an ImageStreamTag, ImageStreamImage, or DockerImage this tag should track.
+type TagReference struct {
+	// Annotations associated with images using this tag.
+	Annotations map[string]string
+	// From is a reference to an image stream tag, image stream image, or Docker image that this
+	// tag should track.
+	From *kapi.ObjectReference
+	// Reference states if the tag should be imported to be used directly, or preserve the reference
+	// that is created rather than being resolved to an image.
+	Reference bool
+	// Generation is the image stream generation that this tag was updated to point at; this value is
+	// set when the tag's from changes and is used to find the correct status entry once the import
+	// or resolution completes.
+	Generation *int64
+	// ImportPolicy controls how images are imported for this tag.
+	ImportPolicy TagImportPolicy
+}
+
+// TagImportPolicy controls how the import of a tag's `from` is handled.
+type TagImportPolicy struct {
+	// Insecure is true if the tag allows import to bypass TLS verification and/or use HTTP
+	// connections to the registry.
+	Insecure bool
+	// Scheduled indicates to the server that this tag should be periodically checked to ensure it
+	// is up to date, and imported.
+	Scheduled bool
+	// Interval is the minimum duration to wait before re-checking a scheduled tag. A zero value
+	// means the server's default interval is used.
+	Interval unversioned.Duration
+	// RequireSignature indicates a DockerImage tag may only be imported if it carries a signature
+	// trusted by the cluster's configured SignaturePolicy.
+	RequireSignature bool
+}
+
+// ImageStreamStatus contains information about the state of this image stream.
+type ImageStreamStatus struct {
+	// DockerImageRepository represents the effective location this stream may be accessed at.
+	// May be empty until the server determines where the repository is located.
+	DockerImageRepository string
+	// Tags are a historical record of images associated with each tag. The first entry in the
+	// TagEvent array is the currently tagged image.
+	Tags map[string]TagEventList
+}
+
+// TagEventList contains a historical record of images associated with a tag.
+type TagEventList struct {
+	Items []TagEvent
+}
+
+// TagEvent is used by ImageStreamStatus to keep a historical record of images associated with a tag.
+type TagEvent struct {
+	// Created holds the time the TagEvent was created.
+	Created unversioned.Time
+	// DockerImageReference is the string that can be used to pull this image.
+	DockerImageReference string
+	// Image is the image that was tagged.
+	Image string
+	// Generation is the spec tag generation that resulted in this tag being updated.
+	Generation int64
+}
@@ -1,8 +1,13 @@
 package imagestream
 
 import (
+	"container/list"
 	"fmt"
+	"hash/fnv"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/golang/glog"
 	kapi "k8s.io/kubernetes/pkg/api"
@@ -14,6 +19,7 @@ import (
 	"k8s.io/kubernetes/pkg/registry/generic"
 	"k8s.io/kubernetes/pkg/runtime"
 	"k8s.io/kubernetes/pkg/util/fielderrors"
+	"k8s.io/kubernetes/pkg/util/flowcontrol"
 	"k8s.io/kubernetes/pkg/util/sets"
 
 	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
@@ -22,10 +28,117 @@ import (
 	"github.com/openshift/origin/pkg/image/api/validation"
 )
 
+// nextImportAnnotationPrefix namespaces the per-tag annotations Strategy uses
+// to track when a scheduled tag is next due for re-import. The full
+// annotation key is nextImportAnnotationPrefix + tag.
+const nextImportAnnotationPrefix = "tag-import.image.openshift.io/"
+
+// defaultScheduledImportInterval is used for a scheduled tag whose
+// ImportPolicy.Interval is unset.
+const defaultScheduledImportInterval = 15 * time.Minute
+
+// TagImportRequest describes a single spec tag that PlanScheduledImports has
+// determined is due for re-resolution against its upstream DockerImage
+// reference.
+type TagImportRequest struct {
+	Tag      string
+	From     kapi.ObjectReference
+	Insecure bool
+}
+
 type ResourceGetter interface {
 	Get(kapi.Context, string) (runtime.Object, error)
 }
 
+// ImageStreamLister looks up a cross-namespace ImageStream without going to
+// etcd, typically backed by a shared informer's indexer. It lets tagsChanged
+// avoid an ImageStreamGetter.Get (and the etcd read behind it) for every
+// unchanged cross-namespace tag on every write to a large stream.
+type ImageStreamLister interface {
+	Get(namespace, name string) (*api.ImageStream, error)
+}
+
+// TagReferenceResolver resolves a spec tag's `from` reference into a concrete
+// TagEvent to be recorded in stream.Status. tagOrID is the value returned by
+// parseFromReference for reference kinds that address another image stream;
+// it is empty for kinds (like DockerImage) that don't need it.
+type TagReferenceResolver interface {
+	Resolve(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error)
+}
+
+// TagReferenceResolverFunc implements TagReferenceResolver for a simple function.
+type TagReferenceResolverFunc func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error)
+
+func (fn TagReferenceResolverFunc) Resolve(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+	return fn(stream, tagRef, tagOrID)
+}
+
+// GitClient resolves a ref (branch, tag, or SHA) in a remote git repository to
+// a commit SHA. It is pluggable so callers can choose how (or whether) to
+// shell out to git, hit a provider API, or stub it out in tests.
+type GitClient interface {
+	ResolveCommit(url, ref string) (string, error)
+}
+
+// ManifestListClient resolves the platform-specific children of a manifest list image by fetching
+// and decoding its manifest list from the backing registry. It is pluggable so this package doesn't
+// need a registry client dependency of its own; a deployment that needs real child materialization
+// registers one on Strategy.ManifestListClient.
+type ManifestListClient interface {
+	Children(ref api.DockerImageReference) ([]api.ManifestListChild, error)
+}
+
+// dockerImageResolver, imageStreamImageResolver, imageStreamTagResolver and
+// imageStreamManifestListResolver back the built-in from.Kind values; they
+// are registered on every Strategy unless explicitly overridden.
+func dockerImageResolver() TagReferenceResolver {
+	return TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+		return &api.TagEvent{
+			Created:              unversioned.Now(),
+			DockerImageReference: tagRef.From.Name,
+		}, nil
+	})
+}
+
+func imageStreamImageResolver() TagReferenceResolver {
+	return TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+		return api.ResolveImageID(stream, tagOrID)
+	})
+}
+
+func imageStreamManifestListResolver() TagReferenceResolver {
+	return TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+		return api.ResolveImageID(stream, tagOrID)
+	})
+}
+
+func imageStreamTagResolver() TagReferenceResolver {
+	return TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+		return api.LatestTaggedImage(stream, tagOrID), nil
+	})
+}
+
+// gitRepositoryResolver treats from.Name as "<url>#<ref>" (defaulting ref to
+// "HEAD"), resolves the current commit SHA via gitClient, and synthesizes a
+// TagEvent whose DockerImageReference encodes the resolved commit so it can
+// be correlated back to the source revision it was built from.
+func gitRepositoryResolver(gitClient GitClient) TagReferenceResolver {
+	return TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+		url, ref := tagRef.From.Name, "HEAD"
+		if parts := strings.SplitN(tagRef.From.Name, "#", 2); len(parts) == 2 {
+			url, ref = parts[0], parts[1]
+		}
+		commit, err := gitClient.ResolveCommit(url, ref)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %q at %q: %v", url, ref, err)
+		}
+		return &api.TagEvent{
+			Created:              unversioned.Now(),
+			DockerImageReference: fmt.Sprintf("%s#%s", url, commit),
+		}, nil
+	})
+}
+
 // Strategy implements behavior for ImageStreams.
 type Strategy struct {
 	runtime.ObjectTyper
@@ -33,6 +146,66 @@ type Strategy struct {
 	defaultRegistry   DefaultRegistry
 	tagVerifier       *TagVerifier
 	ImageStreamGetter ResourceGetter
+
+	// ImageStreamLister, when set, is consulted before ImageStreamGetter for
+	// cross-namespace tag references. It is expected to be backed by a
+	// shared informer and so serves reads from cache instead of etcd.
+	ImageStreamLister ImageStreamLister
+
+	// Resolvers maps a spec tag's from.Kind to the resolver that turns it
+	// into a TagEvent. Built-in kinds are populated by NewStrategy; callers
+	// (including downstream projects) may add additional kinds, such as a
+	// Helm chart reference, by assigning into this map.
+	Resolvers map[string]TagReferenceResolver
+
+	// importRateLimiters bounds how often PlanScheduledImports will allow a
+	// re-import to be requested for a given upstream host, guarding against
+	// many streams that track the same external registry all coming due at
+	// once.
+	importRateLimiters *importRateLimiterRegistry
+
+	// SignaturePolicy, when set, is consulted for every DockerImage tag whose
+	// ImportPolicy.RequireSignature is true before its TagEvent is recorded.
+	// A nil SignaturePolicy means the cluster has no signature enforcement
+	// configured, in which case RequireSignature tags are rejected outright
+	// rather than silently accepted.
+	SignaturePolicy SignaturePolicy
+
+	// ManifestListClient, when set, is consulted to resolve the per-platform
+	// children of an ImageStreamManifestList tag's resolved image. A nil
+	// ManifestListClient means no children are materialized, the same as a
+	// manifest list that happens to have none.
+	ManifestListClient ManifestListClient
+}
+
+// importRateLimiterRegistry lazily creates and shares a token-bucket rate
+// limiter per upstream host. It is held behind a pointer on Strategy so that
+// copying a Strategy value (as happens when embedding it in StatusStrategy
+// and InternalStrategy) shares the same limiters rather than copying a lock.
+type importRateLimiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]flowcontrol.RateLimiter
+}
+
+func newImportRateLimiterRegistry() *importRateLimiterRegistry {
+	return &importRateLimiterRegistry{limiters: make(map[string]flowcontrol.RateLimiter)}
+}
+
+// forHost returns the shared token-bucket rate limiter for host, creating it
+// on first use. host is typically the registry portion of a DockerImage
+// reference (e.g. "registry.example.com").
+func (r *importRateLimiterRegistry) forHost(host string) flowcontrol.RateLimiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limiter, ok := r.limiters[host]
+	if !ok {
+		// allow a small burst per host, then steady-state one import every
+		// few seconds - enough to avoid hammering a registry during a mass
+		// rollout without meaningfully delaying a single stream's import.
+		limiter = flowcontrol.NewTokenBucketRateLimiter(0.5, 5)
+		r.limiters[host] = limiter
+	}
+	return limiter
 }
 
 // NewStrategy is the default logic that applies when creating and updating
@@ -42,10 +215,25 @@ func NewStrategy(defaultRegistry DefaultRegistry, subjectAccessReviewClient subj
 		ObjectTyper:     kapi.Scheme,
 		NameGenerator:   kapi.SimpleNameGenerator,
 		defaultRegistry: defaultRegistry,
-		tagVerifier:     &TagVerifier{subjectAccessReviewClient},
+		tagVerifier:     &TagVerifier{subjectAccessReviewClient: subjectAccessReviewClient, cache: newSARCache()},
+		Resolvers: map[string]TagReferenceResolver{
+			"DockerImage":             dockerImageResolver(),
+			"ImageStreamImage":        imageStreamImageResolver(),
+			"ImageStreamManifestList": imageStreamManifestListResolver(),
+			"ImageStreamTag":          imageStreamTagResolver(),
+		},
+		importRateLimiters: newImportRateLimiterRegistry(),
 	}
 }
 
+// WithGitResolver registers a GitRepository resolver backed by gitClient,
+// allowing an image stream tag to track the HEAD of an upstream source
+// repository directly.
+func (s Strategy) WithGitResolver(gitClient GitClient) Strategy {
+	s.Resolvers["GitRepository"] = gitRepositoryResolver(gitClient)
+	return s
+}
+
 // NamespaceScoped is true for image streams.
 func (s Strategy) NamespaceScoped() bool {
 	return true
@@ -65,6 +253,95 @@ func (s Strategy) PrepareForCreate(obj runtime.Object) {
 		ref.Generation = &stream.Generation
 		stream.Spec.Tags[tag] = ref
 	}
+	stampScheduledImportAnnotations(stream)
+}
+
+// stampScheduledImportAnnotations records, for every spec tag whose
+// ImportPolicy.Scheduled is true, the earliest time it should next be
+// considered for re-import. This turns a one-shot spec evaluation (the tag
+// is only resolved when its `from` changes) into something a periodic
+// reconciler can drive for tags that track a mutable upstream, like
+// `:latest`.
+func stampScheduledImportAnnotations(stream *api.ImageStream) {
+	for tag, ref := range stream.Spec.Tags {
+		annotation := nextImportAnnotationPrefix + tag
+		if ref.From == nil || ref.From.Kind != "DockerImage" || !ref.ImportPolicy.Scheduled {
+			if stream.Annotations != nil {
+				delete(stream.Annotations, annotation)
+			}
+			continue
+		}
+		if _, ok := stream.Annotations[annotation]; ok {
+			// already scheduled; AdvanceScheduledImport advances this once
+			// the import actually runs.
+			continue
+		}
+		interval := ref.ImportPolicy.Interval.Duration
+		if interval <= 0 {
+			interval = defaultScheduledImportInterval
+		}
+		if stream.Annotations == nil {
+			stream.Annotations = make(map[string]string)
+		}
+		stream.Annotations[annotation] = time.Now().Add(interval).Format(time.RFC3339)
+	}
+}
+
+// PlanScheduledImports returns the set of DockerImage tags that are due for
+// re-resolution, based on the NextImport annotations stamped by
+// PrepareForCreate/PrepareForUpdate. The registry's periodic reconcile calls
+// this to decide what to re-import without re-evaluating every tag on every
+// tick. Callers that act on the returned requests should call
+// AdvanceScheduledImport afterwards so the tag isn't immediately replanned.
+func (s Strategy) PlanScheduledImports(stream *api.ImageStream) []TagImportRequest {
+	var due []TagImportRequest
+	now := time.Now()
+	for tag, ref := range stream.Spec.Tags {
+		if ref.From == nil || ref.From.Kind != "DockerImage" || !ref.ImportPolicy.Scheduled {
+			continue
+		}
+		nextImport, ok := stream.Annotations[nextImportAnnotationPrefix+tag]
+		if !ok {
+			continue
+		}
+		when, err := time.Parse(time.RFC3339, nextImport)
+		if err != nil || now.Before(when) {
+			continue
+		}
+
+		host := ref.From.Name
+		if parsed, err := api.ParseDockerImageReference(ref.From.Name); err == nil {
+			host = parsed.Registry
+		}
+		if !s.importRateLimiters.forHost(host).TryAccept() {
+			continue
+		}
+
+		due = append(due, TagImportRequest{
+			Tag:      tag,
+			From:     *ref.From,
+			Insecure: ref.ImportPolicy.Insecure,
+		})
+	}
+	return due
+}
+
+// AdvanceScheduledImport stamps tag's NextImport annotation to the next
+// interval after an import triggered by PlanScheduledImports has run,
+// whether or not it succeeded.
+func (s Strategy) AdvanceScheduledImport(stream *api.ImageStream, tag string) {
+	ref, ok := stream.Spec.Tags[tag]
+	if !ok {
+		return
+	}
+	interval := ref.ImportPolicy.Interval.Duration
+	if interval <= 0 {
+		interval = defaultScheduledImportInterval
+	}
+	if stream.Annotations == nil {
+		stream.Annotations = make(map[string]string)
+	}
+	stream.Annotations[nextImportAnnotationPrefix+tag] = time.Now().Add(interval).Format(time.RFC3339)
 }
 
 // Validate validates a new image stream.
@@ -110,6 +387,28 @@ func (s Strategy) dockerImageRepository(stream *api.ImageStream) string {
 	return ref.String()
 }
 
+// getCrossNamespaceStream resolves a cross-namespace image stream reference,
+// preferring the cache-backed ImageStreamLister over the etcd-backed
+// ImageStreamGetter when one is configured.
+func (s Strategy) getCrossNamespaceStream(namespace, name string) (*api.ImageStream, error) {
+	if s.ImageStreamLister != nil {
+		return s.ImageStreamLister.Get(namespace, name)
+	}
+	obj, err := s.ImageStreamGetter.Get(kapi.WithNamespace(kapi.NewContext(), namespace), name)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.ImageStream), nil
+}
+
+// knownStreamKinds holds the from.Kind values that address a tag or image ID
+// on another image stream (possibly this one). tagsChanged routes every
+// other kind - DockerImage, GitRepository, and any kind a downstream project
+// registers only on Strategy.Resolvers - directly to tagReferenceToTagEvent
+// instead of through parseFromReference, so adding a new external kind never
+// requires touching this file.
+var knownStreamKinds = sets.NewString("ImageStreamTag", "ImageStreamImage", "ImageStreamManifestList")
+
 func parseFromReference(stream *api.ImageStream, from *kapi.ObjectReference) (string, string, error) {
 	splitChar := ""
 	refType := ""
@@ -118,11 +417,11 @@ func parseFromReference(stream *api.ImageStream, from *kapi.ObjectReference) (st
 	case "ImageStreamTag":
 		splitChar = ":"
 		refType = "tag"
-	case "ImageStreamImage":
+	case "ImageStreamImage", "ImageStreamManifestList":
 		splitChar = "@"
 		refType = "id"
 	default:
-		return "", "", fmt.Errorf("invalid from.kind %q - only ImageStreamTag and ImageStreamImage are allowed", from.Kind)
+		return "", "", fmt.Errorf("invalid from.kind %q - only ImageStreamTag, ImageStreamImage, and ImageStreamManifestList are allowed", from.Kind)
 	}
 
 	parts := strings.Split(from.Name, splitChar)
@@ -163,10 +462,20 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 		tagRef.Generation = &generation
 
 		if tagRef.From.Kind == "DockerImage" && len(tagRef.From.Name) > 0 {
+			// RequireSignature gates every DockerImage tag, not just ones
+			// that keep tracking the upstream reference (Reference: true) -
+			// a plain import tag is what actually pulls and records the
+			// image, so it's the one the supply-chain gate must cover.
+			if tagRef.ImportPolicy.RequireSignature {
+				if err := s.verifySignature(stream, tagRef.From.Name); err != nil {
+					errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, err.Error()))
+					continue
+				}
+			}
 			if tagRef.Reference {
-				event, err := tagReferenceToTagEvent(stream, tagRef, "")
+				event, err := s.tagReferenceToTagEvent(stream, tagRef, "")
 				if err != nil {
-					errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from", tag), tagRef.From, err.Error()))
+					errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, err.Error()))
 					continue
 				}
 				stream.Spec.Tags[tag] = tagRef
@@ -175,6 +484,28 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 			continue
 		}
 
+		// Kinds that don't address another image stream - GitRepository, and
+		// any kind a downstream project registers only on s.Resolvers (e.g. a
+		// Helm chart reference) - resolve directly against from.Name, so they
+		// skip parseFromReference and the cross-namespace lookup below
+		// entirely. tagReferenceToTagEvent's resolver-map lookup is the
+		// single source of truth for which kinds are supported; it rejects
+		// anything that isn't registered.
+		if !knownStreamKinds.Has(tagRef.From.Kind) {
+			event, err := s.tagReferenceToTagEvent(stream, tagRef, "")
+			if err != nil {
+				errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, err.Error()))
+				continue
+			}
+			if event == nil {
+				// referenced tag or ID doesn't exist, which is ok
+				continue
+			}
+			stream.Spec.Tags[tag] = tagRef
+			api.AddTagEventToImageStream(stream, tag, *event)
+			continue
+		}
+
 		tagRefStreamName, tagOrID, err := parseFromReference(stream, tagRef.From)
 		if err != nil {
 			errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, "must be of the form <tag>, <repo>:<tag>, <id>, or <repo>@<id>"))
@@ -187,7 +518,7 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 			streamRefNamespace = stream.Namespace
 		}
 		if streamRefNamespace != stream.Namespace || tagRefStreamName != stream.Name {
-			obj, err := s.ImageStreamGetter.Get(kapi.WithNamespace(kapi.NewContext(), streamRefNamespace), tagRefStreamName)
+			resolved, err := s.getCrossNamespaceStream(streamRefNamespace, tagRefStreamName)
 			if err != nil {
 				if kerrors.IsNotFound(err) {
 					errs = append(errs, fielderrors.NewFieldNotFound(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name))
@@ -197,10 +528,10 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 				continue
 			}
 
-			streamRef = obj.(*api.ImageStream)
+			streamRef = resolved
 		}
 
-		event, err := tagReferenceToTagEvent(streamRef, tagRef, tagOrID)
+		event, err := s.tagReferenceToTagEvent(streamRef, tagRef, tagOrID)
 		if err != nil {
 			errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, fmt.Sprintf("error generating tag event: %v", err)))
 			continue
@@ -213,8 +544,42 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 
 		stream.Spec.Tags[tag] = tagRef
 		api.AddTagEventToImageStream(stream, tag, *event)
+
+		if tagRef.From.Kind == "ImageStreamManifestList" {
+			children, err := s.resolveManifestListChildren(*event)
+			if err != nil {
+				errs = append(errs, fielderrors.NewFieldInvalid(fmt.Sprintf("spec.tags[%s].from.name", tag), tagRef.From.Name, fmt.Sprintf("error resolving manifest list children: %v", err)))
+				continue
+			}
+			liveChildTags := sets.NewString()
+			for _, child := range children {
+				childTag := fmt.Sprintf("%s-%s-%s", tag, child.Os, child.Architecture)
+				liveChildTags.Insert(childTag)
+				api.AddTagEventToImageStream(stream, childTag, child.TagEvent)
+				// Record the child in spec.tags too, tracking the resolved
+				// image by ID, so it isn't orphaned: this loop refreshes it
+				// whenever the parent tag re-resolves (UpdateChangedTrackingTags
+				// skips ImageStreamImage tags), and removeStaleManifestListChildren cleans it up
+				// once the manifest list no longer produces it. The parent
+				// annotation is what removeStaleManifestListChildren and
+				// removeOrphanedManifestListChildren use to recognize this
+				// tag as a child of tag, rather than inferring it from name.
+				stream.Spec.Tags[childTag] = api.TagReference{
+					From: &kapi.ObjectReference{
+						Kind: "ImageStreamImage",
+						Name: fmt.Sprintf("%s@%s", stream.Name, child.TagEvent.Image),
+					},
+					Reference:   true,
+					Generation:  tagRef.Generation,
+					Annotations: map[string]string{manifestListChildParentAnnotation: tag},
+				}
+			}
+			removeStaleManifestListChildren(stream, tag, liveChildTags)
+		}
 	}
 
+	removeOrphanedManifestListChildren(stream, old)
+
 	api.UpdateChangedTrackingTags(stream, old)
 
 	// use a consistent timestamp on creation
@@ -230,25 +595,18 @@ func (s Strategy) tagsChanged(old, stream *api.ImageStream) fielderrors.Validati
 	return errs
 }
 
-func tagReferenceToTagEvent(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
-	var (
-		event *api.TagEvent
-		err   error
-	)
-	switch tagRef.From.Kind {
-	case "DockerImage":
-		event = &api.TagEvent{
-			Created:              unversioned.Now(),
-			DockerImageReference: tagRef.From.Name,
-		}
-
-	case "ImageStreamImage":
-		event, err = api.ResolveImageID(stream, tagOrID)
-	case "ImageStreamTag":
-		event, err = api.LatestTaggedImage(stream, tagOrID), nil
-	default:
-		err = fmt.Errorf("invalid from.kind %q: it must be DockerImage, ImageStreamImage or ImageStreamTag", tagRef.From.Kind)
+// tagReferenceToTagEvent resolves tagRef into the TagEvent that should be
+// recorded in stream.Status, dispatching to the resolver registered for
+// tagRef.From.Kind on s.Resolvers.
+func (s Strategy) tagReferenceToTagEvent(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+	resolver, ok := s.Resolvers[tagRef.From.Kind]
+	if !ok {
+		return nil, fmt.Errorf("invalid from.kind %q: no resolver registered for this kind", tagRef.From.Kind)
 	}
+
+	// RequireSignature is enforced by tagsChanged before it calls in here for
+	// every from.Kind that needs it; this is just the resolve step.
+	event, err := resolver.Resolve(stream, tagRef, tagOrID)
 	if err != nil {
 		return nil, err
 	}
@@ -258,6 +616,79 @@ func tagReferenceToTagEvent(stream *api.ImageStream, tagRef api.TagReference, ta
 	return event, nil
 }
 
+// resolveManifestListChildren returns the per-platform children of the manifest list image that
+// event resolved to, using s.ManifestListClient. It returns (nil, nil) when no client is
+// configured, treating "no children" as the default rather than failing validation outright.
+func (s Strategy) resolveManifestListChildren(event api.TagEvent) ([]api.ManifestListChild, error) {
+	if s.ManifestListClient == nil {
+		return nil, nil
+	}
+	ref, err := api.ParseDockerImageReference(event.DockerImageReference)
+	if err != nil {
+		return nil, err
+	}
+	return s.ManifestListClient.Children(ref)
+}
+
+// manifestListChildParentAnnotation marks a spec tag as a per-platform child
+// materialized from an ImageStreamManifestList tag, recording the exact
+// parent tag that created it. Membership in the child namespace is decided
+// solely by this annotation - never by pattern-matching the tag's name -
+// so a user's own tag that merely happens to be named "<tag>-<os>-<arch>"
+// (e.g. "multi-stable") is never mistaken for a child we created and is
+// never touched by the cleanup below.
+const manifestListChildParentAnnotation = "image.openshift.io/manifest-list-parent"
+
+// isManifestListChildOf returns true if ref is a child this package
+// materialized for the manifest list tag named parentTag.
+func isManifestListChildOf(ref api.TagReference, parentTag string) bool {
+	return ref.Annotations[manifestListChildParentAnnotation] == parentTag
+}
+
+// removeStaleManifestListChildren deletes the spec and status tags for any
+// child previously materialized for tag that the manifest list no longer
+// resolves to (e.g. an architecture was dropped from the list).
+func removeStaleManifestListChildren(stream *api.ImageStream, tag string, liveChildTags sets.String) {
+	for childTag, childRef := range stream.Spec.Tags {
+		if isManifestListChildOf(childRef, tag) && !liveChildTags.Has(childTag) {
+			delete(stream.Spec.Tags, childTag)
+			delete(stream.Status.Tags, childTag)
+		}
+	}
+}
+
+// removeOrphanedManifestListChildren deletes every child tag belonging to a
+// parent tag that tagsChanged did not just (re)populate children for -
+// because the parent tag was removed from spec entirely, or its `from` no
+// longer references an ImageStreamManifestList. Without this, the children
+// would never be cleaned up once their parent stops producing them.
+func removeOrphanedManifestListChildren(stream, old *api.ImageStream) {
+	if old == nil {
+		return
+	}
+	for tag, oldRef := range old.Spec.Tags {
+		if oldRef.From == nil || oldRef.From.Kind != "ImageStreamManifestList" {
+			continue
+		}
+		if newRef, ok := stream.Spec.Tags[tag]; ok && newRef.From != nil && newRef.From.Kind == "ImageStreamManifestList" {
+			// still a manifest list tag; the loop above reconciles its
+			// children whenever the tag re-resolves, and leaves them as they
+			// are while it is unchanged.
+			continue
+		}
+		orphaned := sets.NewString()
+		for childTag, childRef := range stream.Spec.Tags {
+			if isManifestListChildOf(childRef, tag) {
+				orphaned.Insert(childTag)
+			}
+		}
+		for _, childTag := range orphaned.List() {
+			delete(stream.Spec.Tags, childTag)
+			delete(stream.Status.Tags, childTag)
+		}
+	}
+}
+
 // tagRefChanged returns true if the tag ref changed between two spec updates.
 func tagRefChanged(old, next api.TagReference, streamNamespace string) bool {
 	if next.From == nil {
@@ -376,8 +807,230 @@ func updateObservedGenerationForStatusUpdate(stream, oldStream *api.ImageStream)
 	}
 }
 
+// SignaturePolicy verifies that an imported DockerImage reference is backed
+// by a signature the cluster trusts before a TagEvent is recorded for it.
+// It gives admins a supply-chain gate at the API level, mirroring the
+// policy-enforcement pattern Kubernetes admission plugins already use.
+type SignaturePolicy interface {
+	VerifySignature(ref api.DockerImageReference, stream *api.ImageStream) error
+}
+
+// SignatureSource retrieves the known signatures for a specific image
+// digest, e.g. from an atomic signature store or a registry's extensions
+// API. It is pluggable so SignaturePolicy implementations don't hard-code a
+// transport for fetching signature blobs.
+type SignatureSource interface {
+	Signatures(ref api.DockerImageReference) ([]ImageSignature, error)
+}
+
+// ImageSignature is a single detached signature over an image digest.
+type ImageSignature struct {
+	// Fingerprint identifies the public key the signature claims to be signed
+	// with, and is matched against the trusted keys in the policy's ConfigMap.
+	Fingerprint string
+	Content     []byte
+}
+
+// SignatureVerifier checks a signature's content against the public key
+// material registered for its fingerprint.
+type SignatureVerifier interface {
+	Verify(publicKeyPEM string, sig ImageSignature) error
+}
+
+// configMapSignaturePolicy implements SignaturePolicy by trusting the signer
+// public keys listed in a namespace-scoped ConfigMap, fingerprint -> PEM
+// encoded public key.
+type configMapSignaturePolicy struct {
+	configMaps    ConfigMapGetter
+	configMapName string
+	signatures    SignatureSource
+	verifier      SignatureVerifier
+}
+
+// ConfigMapGetter retrieves a ConfigMap from a given namespace; it is
+// typically backed by a kapi.ConfigMaps() client or an informer lister.
+type ConfigMapGetter interface {
+	Get(namespace, name string) (*kapi.ConfigMap, error)
+}
+
+// NewConfigMapSignaturePolicy returns a SignaturePolicy backed by the allowed
+// signer keys in the namespace-scoped ConfigMap configMapName.
+func NewConfigMapSignaturePolicy(configMaps ConfigMapGetter, configMapName string, signatures SignatureSource, verifier SignatureVerifier) SignaturePolicy {
+	return &configMapSignaturePolicy{
+		configMaps:    configMaps,
+		configMapName: configMapName,
+		signatures:    signatures,
+		verifier:      verifier,
+	}
+}
+
+func (p *configMapSignaturePolicy) VerifySignature(ref api.DockerImageReference, stream *api.ImageStream) error {
+	if len(ref.ID) == 0 {
+		return fmt.Errorf("%q does not pin a digest - floating tags cannot be verified against a signature", ref.Exact())
+	}
+
+	trusted, err := p.configMaps.Get(stream.Namespace, p.configMapName)
+	if err != nil {
+		return fmt.Errorf("unable to load trusted signer keys from configmap %s/%s: %v", stream.Namespace, p.configMapName, err)
+	}
+
+	sigs, err := p.signatures.Signatures(ref)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve signatures for %s: %v", ref.Exact(), err)
+	}
+
+	var failedFingerprints []string
+	for _, sig := range sigs {
+		publicKey, ok := trusted.Data[sig.Fingerprint]
+		if !ok {
+			failedFingerprints = append(failedFingerprints, sig.Fingerprint)
+			continue
+		}
+		if err := p.verifier.Verify(publicKey, sig); err != nil {
+			failedFingerprints = append(failedFingerprints, sig.Fingerprint)
+			continue
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no trusted signature found for %s (untrusted or invalid signer fingerprints: %s)", ref.Exact(), strings.Join(failedFingerprints, ", "))
+}
+
+// verifySignature enforces RequireSignature for a DockerImage `from.name`,
+// reporting the signer fingerprint that failed so admins can tell an
+// untrusted signer from a missing one.
+func (s Strategy) verifySignature(stream *api.ImageStream, fromName string) error {
+	if s.SignaturePolicy == nil {
+		return fmt.Errorf("signature required but no SignaturePolicy is configured for this cluster")
+	}
+	ref, err := api.ParseDockerImageReference(fromName)
+	if err != nil {
+		return err
+	}
+	return s.SignaturePolicy.VerifySignature(ref, stream)
+}
+
+// sarCacheTTL bounds how long a cross-namespace SubjectAccessReview result is
+// trusted before it must be re-checked.
+const sarCacheTTL = 30 * time.Second
+
+// sarCacheSize is the maximum number of distinct (user, namespace, stream)
+// results the cache retains before evicting the least recently used entry.
+const sarCacheSize = 1024
+
+// sarCacheKey identifies a cached SubjectAccessReview result. groupsHash is
+// derived from the user's name and groups (see userGroupsHash), so a group
+// membership change naturally misses the cache instead of serving a stale
+// decision for the old group set.
+type sarCacheKey struct {
+	groupsHash string
+	namespace  string
+	streamName string
+}
+
+type sarCacheEntry struct {
+	key       sarCacheKey
+	allowed   bool
+	expiresAt time.Time
+}
+
+// sarCache is a small bounded LRU+TTL cache of SubjectAccessReview results,
+// so that repeated writes to a stream with many unchanged cross-namespace
+// tracking tags don't re-issue a SAR for each one.
+type sarCache struct {
+	mu      sync.Mutex
+	order   *list.List // of *sarCacheEntry, front = most recently used
+	entries map[sarCacheKey]*list.Element
+}
+
+func newSARCache() *sarCache {
+	return &sarCache{
+		order:   list.New(),
+		entries: make(map[sarCacheKey]*list.Element),
+	}
+}
+
+func (c *sarCache) get(key sarCacheKey) (allowed, found bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		return false, false
+	}
+	entry := elem.Value.(*sarCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.entries, key)
+		return false, false
+	}
+	c.order.MoveToFront(elem)
+	return entry.allowed, true
+}
+
+func (c *sarCache) set(key sarCacheKey, allowed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*sarCacheEntry).allowed = allowed
+		elem.Value.(*sarCacheEntry).expiresAt = time.Now().Add(sarCacheTTL)
+		c.order.MoveToFront(elem)
+		return
+	}
+	entry := &sarCacheEntry{key: key, allowed: allowed, expiresAt: time.Now().Add(sarCacheTTL)}
+	c.entries[key] = c.order.PushFront(entry)
+	if c.order.Len() > sarCacheSize {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*sarCacheEntry).key)
+	}
+}
+
+// invalidateStream drops every cached result for namespace/streamName,
+// regardless of which user it was cached for. Called when the referenced
+// ImageStream is deleted, since a deletion can change who is allowed to
+// `get` a name that may be recreated with different permissions.
+func (c *sarCache) invalidateStream(namespace, streamName string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		entry := elem.Value.(*sarCacheEntry)
+		if entry.key.namespace == namespace && entry.key.streamName == streamName {
+			c.order.Remove(elem)
+			delete(c.entries, entry.key)
+		}
+		elem = next
+	}
+}
+
+// userGroupsHash derives a stable, short key from a user's identity and
+// group membership so it can be folded into a cache key. Two calls with the
+// same name and the same set of groups (regardless of order) hash equal.
+func userGroupsHash(user user.Info) string {
+	groups := append([]string{}, user.GetGroups()...)
+	sort.Strings(groups)
+	h := fnv.New64a()
+	h.Write([]byte(user.GetName()))
+	for _, g := range groups {
+		h.Write([]byte{0})
+		h.Write([]byte(g))
+	}
+	return fmt.Sprintf("%x", h.Sum64())
+}
+
 type TagVerifier struct {
 	subjectAccessReviewClient subjectaccessreview.Registry
+	cache                     *sarCache
+}
+
+// InvalidateImageStream drops any cached SubjectAccessReview results for the
+// given stream. Call this from the ImageStream delete handler so a future
+// reference to a recreated stream of the same name is re-checked rather than
+// served a stale decision from the cache.
+func (v *TagVerifier) InvalidateImageStream(namespace, name string) {
+	if v.cache != nil {
+		v.cache.invalidateStream(namespace, name)
+	}
 }
 
 func (v *TagVerifier) Verify(old, stream *api.ImageStream, user user.Info) fielderrors.ValidationErrorList {
@@ -386,6 +1039,7 @@ func (v *TagVerifier) Verify(old, stream *api.ImageStream, user user.Info) field
 	if old != nil && old.Spec.Tags != nil {
 		oldTags = old.Spec.Tags
 	}
+	groupsHash := userGroupsHash(user)
 	for tag, tagRef := range stream.Spec.Tags {
 		if tagRef.From == nil {
 			continue
@@ -406,6 +1060,16 @@ func (v *TagVerifier) Verify(old, stream *api.ImageStream, user user.Info) field
 			continue
 		}
 
+		cacheKey := sarCacheKey{groupsHash: groupsHash, namespace: tagRef.From.Namespace, streamName: streamName}
+		if v.cache != nil {
+			if allowed, found := v.cache.get(cacheKey); found {
+				if !allowed {
+					errors = append(errors, fielderrors.NewFieldForbidden(fmt.Sprintf("spec.tags[%s].from", tag), fmt.Sprintf("%s/%s", tagRef.From.Namespace, streamName)))
+				}
+				continue
+			}
+		}
+
 		subjectAccessReview := authorizationapi.SubjectAccessReview{
 			Action: authorizationapi.AuthorizationAttributes{
 				Verb:         "get",
@@ -418,7 +1082,15 @@ func (v *TagVerifier) Verify(old, stream *api.ImageStream, user user.Info) field
 		ctx := kapi.WithNamespace(kapi.NewContext(), tagRef.From.Namespace)
 		glog.V(4).Infof("Performing SubjectAccessReview for user=%s, groups=%v to %s/%s", user.GetName(), user.GetGroups(), tagRef.From.Namespace, streamName)
 		resp, err := v.subjectAccessReviewClient.CreateSubjectAccessReview(ctx, &subjectAccessReview)
-		if err != nil || resp == nil || (resp != nil && !resp.Allowed) {
+		allowed := err == nil && resp != nil && resp.Allowed
+		if v.cache != nil && err == nil {
+			// only cache a result we actually got an answer for - a transient
+			// SAR error (network blip, apiserver hiccup) must not pin a
+			// "forbidden" decision in the cache for sarCacheTTL; the next
+			// write to this stream should simply retry the SAR.
+			v.cache.set(cacheKey, allowed)
+		}
+		if !allowed {
 			errors = append(errors, fielderrors.NewFieldForbidden(fmt.Sprintf("spec.tags[%s].from", tag), fmt.Sprintf("%s/%s", tagRef.From.Namespace, streamName)))
 			continue
 		}
@@ -449,6 +1121,8 @@ func (s Strategy) prepareForUpdate(obj, old runtime.Object, resetStatus bool) {
 
 	// default spec tag generations afterwards (to avoid updating the generation for legacy objects)
 	ensureSpecTagGenerationsAreSet(stream, oldStream)
+
+	stampScheduledImportAnnotations(stream)
 }
 
 func (s Strategy) PrepareForUpdate(obj, old runtime.Object) {
@@ -480,6 +1154,14 @@ func (s Strategy) Decorate(obj runtime.Object) error {
 	return nil
 }
 
+// OnImageStreamDelete drops any cached SubjectAccessReview results for the
+// deleted stream. The registry's delete handler should call this so a
+// future cross-namespace reference to a recreated stream of the same name
+// is re-checked instead of served a stale cached decision.
+func (s Strategy) OnImageStreamDelete(namespace, name string) {
+	s.tagVerifier.InvalidateImageStream(namespace, name)
+}
+
 type StatusStrategy struct {
 	Strategy
 }
@@ -0,0 +1,157 @@
+package imagestream
+
+import (
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/util/sets"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// fakeManifestListClient returns a fixed set of children for every ref, regardless of input,
+// so tests can assert on exactly what tagsChanged does with them.
+type fakeManifestListClient struct {
+	children []api.ManifestListChild
+}
+
+func (c fakeManifestListClient) Children(ref api.DockerImageReference) ([]api.ManifestListChild, error) {
+	return c.children, nil
+}
+
+// streamWithManifestListTag returns a stream with a single ImageStreamManifestList tag whose
+// target already has a resolved status entry.
+func streamWithManifestListTag(tag string) *api.ImageStream {
+	return &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				tag: {From: &kapi.ObjectReference{Kind: "ImageStreamManifestList", Name: "is@sha256:abc"}},
+			},
+		},
+		Status: api.ImageStreamStatus{
+			Tags: map[string]api.TagEventList{
+				"list": {Items: []api.TagEvent{{Image: "sha256:abc", DockerImageReference: "is@sha256:abc"}}},
+			},
+		},
+	}
+}
+
+// TestTagsChangedRemovesOrphanedManifestListChildrenOnRemoval confirms that when a manifest list
+// tag's children were materialized into spec.tags and the parent tag is later removed entirely,
+// the orphaned "<tag>-<os>-<arch>" entries are cleaned up rather than left behind forever.
+func TestTagsChangedRemovesOrphanedManifestListChildrenOnRemoval(t *testing.T) {
+	s := newTestStrategy()
+
+	stream := streamWithManifestListTag("multi")
+	// Simulate children that a prior reconcile already materialized for this tag.
+	stream.Spec.Tags["multi-linux-amd64"] = api.TagReference{
+		From:        &kapi.ObjectReference{Kind: "ImageStreamImage", Name: "is@sha256:amd64"},
+		Reference:   true,
+		Annotations: map[string]string{manifestListChildParentAnnotation: "multi"},
+	}
+	stream.Status.Tags["multi-linux-amd64"] = api.TagEventList{Items: []api.TagEvent{{Image: "sha256:amd64"}}}
+
+	old := stream
+	stream = &api.ImageStream{
+		ObjectMeta: old.ObjectMeta,
+		Spec: api.ImageStreamSpec{Tags: map[string]api.TagReference{
+			// the parent "multi" tag was removed by the client, but the previously materialized
+			// child tag was carried forward unchanged, same as any other tag the client didn't touch.
+			"multi-linux-amd64": old.Spec.Tags["multi-linux-amd64"],
+		}},
+		Status: api.ImageStreamStatus{Tags: map[string]api.TagEventList{"multi-linux-amd64": old.Status.Tags["multi-linux-amd64"]}},
+	}
+
+	s.tagsChanged(old, stream)
+
+	if _, ok := stream.Spec.Tags["multi-linux-amd64"]; ok {
+		t.Fatalf("expected the orphaned child spec tag to be removed once its parent tag was deleted")
+	}
+	if _, ok := stream.Status.Tags["multi-linux-amd64"]; ok {
+		t.Fatalf("expected the orphaned child status tag to be removed once its parent tag was deleted")
+	}
+}
+
+// TestTagsChangedMaterializesManifestListChildren confirms that, with a ManifestListClient
+// configured, resolving an ImageStreamManifestList tag actually materializes a "<tag>-<os>-<arch>"
+// spec and status tag per platform child, each annotated with the parent tag that created it.
+func TestTagsChangedMaterializesManifestListChildren(t *testing.T) {
+	s := newTestStrategy()
+	s.Resolvers["ImageStreamManifestList"] = imageStreamManifestListResolver()
+	// child tags added to spec.tags mid-loop may be visited by the same loop
+	s.Resolvers["ImageStreamImage"] = imageStreamImageResolver()
+	s.ManifestListClient = fakeManifestListClient{children: []api.ManifestListChild{
+		{Os: "linux", Architecture: "amd64", TagEvent: api.TagEvent{Image: "sha256:amd64"}},
+		{Os: "linux", Architecture: "arm64", TagEvent: api.TagEvent{Image: "sha256:arm64"}},
+	}}
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"multi": {From: &kapi.ObjectReference{Kind: "ImageStreamManifestList", Name: "is@sha256:abc"}},
+			},
+		},
+		Status: api.ImageStreamStatus{
+			Tags: map[string]api.TagEventList{
+				"multi": {Items: []api.TagEvent{{Image: "sha256:abc", DockerImageReference: "is@sha256:abc"}}},
+			},
+		},
+	}
+
+	if errs := s.tagsChanged(nil, stream); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	for _, tc := range []struct{ tag, image string }{
+		{"multi-linux-amd64", "sha256:amd64"},
+		{"multi-linux-arm64", "sha256:arm64"},
+	} {
+		childRef, ok := stream.Spec.Tags[tc.tag]
+		if !ok {
+			t.Fatalf("expected child spec tag %s to be materialized", tc.tag)
+		}
+		if childRef.Annotations[manifestListChildParentAnnotation] != "multi" {
+			t.Fatalf("expected child tag %s to be annotated with its parent tag, got %#v", tc.tag, childRef.Annotations)
+		}
+		events, ok := stream.Status.Tags[tc.tag]
+		if !ok || len(events.Items) == 0 || events.Items[0].Image != tc.image {
+			t.Fatalf("expected child status tag %s to record image %s, got %#v", tc.tag, tc.image, events)
+		}
+	}
+}
+
+// TestRemoveStaleManifestListChildrenPreservesUnrelatedSimilarlyNamedTag confirms that a user's own
+// tag which merely happens to share the "<parentTag>-..." name shape as a manifest list child (e.g.
+// "multi-stable" next to a "multi" manifest list) is never deleted by cleanup, since membership is
+// decided by the child's parent annotation rather than by its name.
+func TestRemoveStaleManifestListChildrenPreservesUnrelatedSimilarlyNamedTag(t *testing.T) {
+	stream := &api.ImageStream{
+		Spec: api.ImageStreamSpec{Tags: map[string]api.TagReference{
+			"multi-stable": {From: &kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/repo:stable"}},
+			"multi-linux-amd64": {
+				From:        &kapi.ObjectReference{Kind: "ImageStreamImage", Name: "is@sha256:amd64"},
+				Reference:   true,
+				Annotations: map[string]string{manifestListChildParentAnnotation: "multi"},
+			},
+		}},
+		Status: api.ImageStreamStatus{Tags: map[string]api.TagEventList{
+			"multi-stable":      {Items: []api.TagEvent{{Image: "sha256:stable"}}},
+			"multi-linux-amd64": {Items: []api.TagEvent{{Image: "sha256:amd64"}}},
+		}},
+	}
+
+	// simulate the manifest list no longer resolving to any children this pass.
+	removeStaleManifestListChildren(stream, "multi", sets.NewString())
+
+	if _, ok := stream.Spec.Tags["multi-stable"]; !ok {
+		t.Fatalf("expected the unrelated user tag multi-stable to survive cleanup of multi's children")
+	}
+	if _, ok := stream.Status.Tags["multi-stable"]; !ok {
+		t.Fatalf("expected the unrelated user tag multi-stable's status entry to survive cleanup")
+	}
+	if _, ok := stream.Spec.Tags["multi-linux-amd64"]; ok {
+		t.Fatalf("expected the stale child tag multi-linux-amd64 to be removed")
+	}
+}
@@ -0,0 +1,96 @@
+package imagestream
+
+import (
+	"fmt"
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// TestTagsChangedRoutesCustomKindToRegisteredResolver confirms a downstream project can add a
+// kind that doesn't address another image stream (e.g. a Helm chart reference) purely by
+// registering it on Strategy.Resolvers, without this package special-casing the kind by name.
+func TestTagsChangedRoutesCustomKindToRegisteredResolver(t *testing.T) {
+	resolved := false
+	s := Strategy{
+		Resolvers: map[string]TagReferenceResolver{
+			"Helm": TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+				resolved = true
+				return &api.TagEvent{DockerImageReference: tagRef.From.Name}, nil
+			}),
+		},
+	}
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"chart": {From: &kapi.ObjectReference{Kind: "Helm", Name: "example/chart:1.0.0"}},
+			},
+		},
+	}
+
+	if errs := s.tagsChanged(nil, stream); len(errs) != 0 {
+		t.Fatalf("unexpected errors routing a registered custom kind: %v", errs)
+	}
+	if !resolved {
+		t.Fatalf("expected the Helm resolver to have been invoked")
+	}
+	if _, ok := stream.Status.Tags["chart"]; !ok {
+		t.Fatalf("expected a status tag to be recorded for the resolved custom kind")
+	}
+}
+
+// TestTagsChangedGenericDispatchToleratesNilEvent confirms a registered resolver for a generic kind
+// that returns a nil TagEvent (the same "not found yet" signal imageStreamTagResolver uses) is
+// skipped rather than dereferenced, mirroring the nil-event guard on the stream-addressing path.
+func TestTagsChangedGenericDispatchToleratesNilEvent(t *testing.T) {
+	s := Strategy{
+		Resolvers: map[string]TagReferenceResolver{
+			"Helm": TagReferenceResolverFunc(func(stream *api.ImageStream, tagRef api.TagReference, tagOrID string) (*api.TagEvent, error) {
+				return nil, nil
+			}),
+		},
+	}
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"chart": {From: &kapi.ObjectReference{Kind: "Helm", Name: "example/chart:1.0.0"}},
+			},
+		},
+	}
+
+	if errs := s.tagsChanged(nil, stream); len(errs) != 0 {
+		t.Fatalf("unexpected errors from a nil-event resolve: %v", errs)
+	}
+	if _, ok := stream.Status.Tags["chart"]; ok {
+		t.Fatalf("expected no status tag to be recorded when the resolver returns a nil event")
+	}
+}
+
+// TestTagsChangedRejectsUnregisteredKind confirms a kind with no resolver registered anywhere is
+// still rejected, rather than silently accepted by the generic dispatch path.
+func TestTagsChangedRejectsUnregisteredKind(t *testing.T) {
+	s := Strategy{Resolvers: map[string]TagReferenceResolver{}}
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"chart": {From: &kapi.ObjectReference{Kind: "Helm", Name: "example/chart:1.0.0"}},
+			},
+		},
+	}
+
+	errs := s.tagsChanged(nil, stream)
+	if len(errs) == 0 {
+		t.Fatalf("expected an unregistered kind to be rejected")
+	}
+	if msg := fmt.Sprint(errs[0]); len(msg) == 0 {
+		t.Fatalf("expected a non-empty error message for the unregistered kind")
+	}
+}
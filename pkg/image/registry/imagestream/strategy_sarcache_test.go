@@ -0,0 +1,127 @@
+package imagestream
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+	"k8s.io/kubernetes/pkg/auth/user"
+
+	authorizationapi "github.com/openshift/origin/pkg/authorization/api"
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// fakeSARClient lets tests control whether CreateSubjectAccessReview returns an error, a denial, or
+// an allow, and counts how many times it was actually invoked.
+type fakeSARClient struct {
+	calls   int
+	err     error
+	allowed bool
+}
+
+func (f *fakeSARClient) CreateSubjectAccessReview(ctx kapi.Context, sar *authorizationapi.SubjectAccessReview) (*authorizationapi.SubjectAccessReviewResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &authorizationapi.SubjectAccessReviewResponse{Allowed: f.allowed}, nil
+}
+
+func crossNamespaceStream(tag, namespace, name string) (*api.ImageStream, *api.ImageStream) {
+	old := &api.ImageStream{ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"}}
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				tag: {From: &kapi.ObjectReference{Kind: "ImageStreamTag", Namespace: namespace, Name: name + ":latest"}},
+			},
+		},
+	}
+	return old, stream
+}
+
+// TestTagVerifierDoesNotCacheTransientErrors confirms that a SAR call that fails transiently (a
+// network blip, an apiserver hiccup) is retried on the very next write instead of pinning a
+// "forbidden" decision in the cache for sarCacheTTL.
+func TestTagVerifierDoesNotCacheTransientErrors(t *testing.T) {
+	client := &fakeSARClient{err: fmt.Errorf("etcd is unavailable")}
+	v := &TagVerifier{subjectAccessReviewClient: client, cache: newSARCache()}
+	u := &user.DefaultInfo{Name: "alice"}
+
+	old, stream := crossNamespaceStream("t", "other", "source")
+
+	if errs := v.Verify(old, stream, u); len(errs) == 0 {
+		t.Fatalf("expected the first (errored) SAR call to be treated as forbidden")
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected 1 SAR call, got %d", client.calls)
+	}
+
+	client.err = nil
+	client.allowed = true
+	if errs := v.Verify(old, stream, u); len(errs) != 0 {
+		t.Fatalf("unexpected errors on retry: %v", errs)
+	}
+	if client.calls != 2 {
+		t.Fatalf("expected the transient error not to be cached, so the retry should re-issue the SAR; got %d calls", client.calls)
+	}
+}
+
+// TestTagVerifierCachesSuccessfulResult confirms a real (non-errored) decision is stored in the
+// cache under the key Verify looks up, after exactly one SAR.
+func TestTagVerifierCachesSuccessfulResult(t *testing.T) {
+	client := &fakeSARClient{allowed: true}
+	v := &TagVerifier{subjectAccessReviewClient: client, cache: newSARCache()}
+	u := &user.DefaultInfo{Name: "alice"}
+
+	old, stream := crossNamespaceStream("t", "other", "source")
+
+	if errs := v.Verify(old, stream, u); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	// inspect the cached entry directly, under the same key Verify builds for this tag.
+	if allowed, found := v.cache.get(sarCacheKey{groupsHash: userGroupsHash(u), namespace: "other", streamName: "source"}); !found || !allowed {
+		t.Fatalf("expected a successful SAR result to be cached as allowed, got found=%v allowed=%v", found, allowed)
+	}
+	if client.calls != 1 {
+		t.Fatalf("expected exactly 1 SAR call, got %d", client.calls)
+	}
+}
+
+func TestSARCacheTTLExpiry(t *testing.T) {
+	c := newSARCache()
+	key := sarCacheKey{groupsHash: "h", namespace: "ns", streamName: "is"}
+	c.set(key, true)
+
+	c.mu.Lock()
+	c.entries[key].Value.(*sarCacheEntry).expiresAt = time.Now().Add(-time.Second)
+	c.mu.Unlock()
+
+	if _, found := c.get(key); found {
+		t.Fatalf("expected an expired entry to miss the cache")
+	}
+}
+
+func TestSARCacheLRUEviction(t *testing.T) {
+	c := newSARCache()
+	for i := 0; i < sarCacheSize+10; i++ {
+		c.set(sarCacheKey{groupsHash: "h", namespace: "ns", streamName: fmt.Sprintf("is-%d", i)}, true)
+	}
+	if c.order.Len() != sarCacheSize {
+		t.Fatalf("expected the cache to be bounded at %d entries, got %d", sarCacheSize, c.order.Len())
+	}
+	if _, found := c.get(sarCacheKey{groupsHash: "h", namespace: "ns", streamName: "is-0"}); found {
+		t.Fatalf("expected the least recently used entry to have been evicted")
+	}
+}
+
+func TestSARCacheInvalidateStream(t *testing.T) {
+	c := newSARCache()
+	key := sarCacheKey{groupsHash: "h", namespace: "ns", streamName: "is"}
+	c.set(key, true)
+	c.invalidateStream("ns", "is")
+	if _, found := c.get(key); found {
+		t.Fatalf("expected invalidateStream to drop the cached result")
+	}
+}
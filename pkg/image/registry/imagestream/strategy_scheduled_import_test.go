@@ -0,0 +1,80 @@
+package imagestream
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+// TestImportRateLimiterRegistrySharesLimiterPerHost confirms the registry hands back the same
+// limiter for repeated lookups of the same host (so a steady stream of writes to unrelated streams
+// tracking the same registry shares one budget) and distinct limiters for distinct hosts.
+func TestImportRateLimiterRegistrySharesLimiterPerHost(t *testing.T) {
+	r := newImportRateLimiterRegistry()
+	a := r.forHost("registry.example.com")
+	b := r.forHost("registry.example.com")
+	if a != b {
+		t.Fatalf("expected forHost to return the same limiter for repeated calls with the same host")
+	}
+	if c := r.forHost("other.example.com"); a == c {
+		t.Fatalf("expected forHost to return distinct limiters for distinct hosts")
+	}
+}
+
+// TestPlanScheduledImportsRespectsPerHostRateLimit verifies that many scheduled tags due for
+// re-import against the same upstream host are throttled by the shared per-host rate limiter,
+// instead of all being planned for import in a single pass.
+func TestPlanScheduledImportsRespectsPerHostRateLimit(t *testing.T) {
+	s := Strategy{importRateLimiters: newImportRateLimiterRegistry()}
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339)
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Annotations: map[string]string{}},
+		Spec:       api.ImageStreamSpec{Tags: map[string]api.TagReference{}},
+	}
+	const tagCount = 7
+	for i := 0; i < tagCount; i++ {
+		tag := fmt.Sprintf("v%d", i)
+		stream.Spec.Tags[tag] = api.TagReference{
+			From:         &kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/repo:latest"},
+			ImportPolicy: api.TagImportPolicy{Scheduled: true},
+		}
+		stream.Annotations[nextImportAnnotationPrefix+tag] = past
+	}
+
+	due := s.PlanScheduledImports(stream)
+	if len(due) == 0 {
+		t.Fatalf("expected at least one due tag to be accepted under the per-host rate limit")
+	}
+	if len(due) >= tagCount {
+		t.Fatalf("expected the per-host rate limiter to reject some of %d simultaneously due tags, got all %d planned", tagCount, len(due))
+	}
+}
+
+// TestPlanScheduledImportsSkipsUnscheduledAndNotDueTags confirms PlanScheduledImports only plans
+// tags that are both Scheduled and past their NextImport annotation.
+func TestPlanScheduledImportsSkipsUnscheduledAndNotDueTags(t *testing.T) {
+	s := Strategy{importRateLimiters: newImportRateLimiterRegistry()}
+	future := time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Annotations: map[string]string{
+			nextImportAnnotationPrefix + "not-due": future,
+		}},
+		Spec: api.ImageStreamSpec{Tags: map[string]api.TagReference{
+			"unscheduled": {From: &kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/repo:latest"}},
+			"not-due": {
+				From:         &kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/repo:latest"},
+				ImportPolicy: api.TagImportPolicy{Scheduled: true},
+			},
+		}},
+	}
+
+	if due := s.PlanScheduledImports(stream); len(due) != 0 {
+		t.Fatalf("expected no tags to be due, got %v", due)
+	}
+}
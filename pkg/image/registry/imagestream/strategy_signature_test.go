@@ -0,0 +1,73 @@
+package imagestream
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	kapi "k8s.io/kubernetes/pkg/api"
+
+	"github.com/openshift/origin/pkg/image/api"
+)
+
+type rejectAllSignaturePolicy struct {
+	calls []api.DockerImageReference
+	err   error
+}
+
+func (p *rejectAllSignaturePolicy) VerifySignature(ref api.DockerImageReference, stream *api.ImageStream) error {
+	p.calls = append(p.calls, ref)
+	return p.err
+}
+
+func newTestStrategy() Strategy {
+	return Strategy{
+		Resolvers: map[string]TagReferenceResolver{
+			"DockerImage": dockerImageResolver(),
+		},
+	}
+}
+
+// TestTagsChangedEnforcesSignatureOnNonReferenceDockerImageTag verifies that a plain import tag
+// (Reference: false), the common case that actually records the image, is rejected when
+// RequireSignature is set and the configured SignaturePolicy refuses it. Before this fix,
+// RequireSignature was only enforced for Reference: true tags.
+func TestTagsChangedEnforcesSignatureOnNonReferenceDockerImageTag(t *testing.T) {
+	policy := &rejectAllSignaturePolicy{err: errUntrustedSignature}
+	s := newTestStrategy()
+	s.SignaturePolicy = policy
+
+	stream := &api.ImageStream{
+		ObjectMeta: kapi.ObjectMeta{Namespace: "ns", Name: "is"},
+		Spec: api.ImageStreamSpec{
+			Tags: map[string]api.TagReference{
+				"v1": {
+					From:         &kapi.ObjectReference{Kind: "DockerImage", Name: "registry.example.com/repo@sha256:deadbeef"},
+					Reference:    false,
+					ImportPolicy: api.TagImportPolicy{RequireSignature: true},
+				},
+			},
+		},
+	}
+
+	errs := s.tagsChanged(nil, stream)
+	if len(errs) == 0 {
+		t.Fatalf("expected tagsChanged to report a signature error for an unsigned non-reference DockerImage tag")
+	}
+	if msg := fmt.Sprint(errs[0]); !strings.Contains(msg, "spec.tags[v1].from.name") {
+		t.Fatalf("expected the signature error to be reported on spec.tags[v1].from.name, got %q", msg)
+	}
+	if len(policy.calls) != 1 {
+		t.Fatalf("expected SignaturePolicy.VerifySignature to be called exactly once, got %d", len(policy.calls))
+	}
+	if _, ok := stream.Status.Tags["v1"]; ok {
+		t.Fatalf("an image that failed signature verification must not be recorded in status.tags")
+	}
+}
+
+// errUntrustedSignature is a stand-in verification failure for tests.
+var errUntrustedSignature = &signatureError{"no trusted signature found"}
+
+type signatureError struct{ msg string }
+
+func (e *signatureError) Error() string { return e.msg }